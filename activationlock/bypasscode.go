@@ -0,0 +1,51 @@
+// Package activationlock implements bypass code and escrow key generation
+// for Apple's Activation Lock, per the algorithm documented for MDM vendors
+// implementing the DEP "Device Lock" API.
+package activationlock
+
+import (
+	"crypto/rand"
+	"math/big"
+	"strings"
+)
+
+const (
+	bypassCodeGroups   = 6
+	bypassCodeGroupLen = 5
+
+	// bypassCodeAlphabet is uppercase alphanumeric with 0/O and 1/I removed,
+	// since they are easily confused with each other when transcribed by
+	// hand off a screen.
+	bypassCodeAlphabet = "ABCDEFGHJKLMNPQRSTUVWXYZ23456789"
+)
+
+// GenerateBypassCode generates a random Activation Lock bypass code, as a
+// string of bypassCodeGroups groups of bypassCodeGroupLen characters each,
+// separated by hyphens (e.g. "ABCDE-FGHJK-..."). The resulting code is
+// suitable for use with EscrowKey and should be stored by the caller: it is
+// the only way to later bypass Activation Lock on the device it is issued
+// for.
+func GenerateBypassCode() (string, error) {
+	groups := make([]string, bypassCodeGroups)
+	for i := range groups {
+		group, err := randomAlphabetString(bypassCodeGroupLen)
+		if err != nil {
+			return "", err
+		}
+		groups[i] = group
+	}
+	return strings.Join(groups, "-"), nil
+}
+
+func randomAlphabetString(n int) (string, error) {
+	alphabetLen := big.NewInt(int64(len(bypassCodeAlphabet)))
+	out := make([]byte, n)
+	for i := range out {
+		idx, err := rand.Int(rand.Reader, alphabetLen)
+		if err != nil {
+			return "", err
+		}
+		out[i] = bypassCodeAlphabet[idx.Int64()]
+	}
+	return string(out), nil
+}
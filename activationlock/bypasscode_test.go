@@ -0,0 +1,50 @@
+package activationlock
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestGenerateBypassCodeShape(t *testing.T) {
+	for i := 0; i < 100; i++ {
+		code, err := GenerateBypassCode()
+		if err != nil {
+			t.Fatalf("GenerateBypassCode returned error: %v", err)
+		}
+
+		groups := strings.Split(code, "-")
+		if len(groups) != bypassCodeGroups {
+			t.Fatalf("code %q has %d groups, want %d", code, len(groups), bypassCodeGroups)
+		}
+		for _, group := range groups {
+			if len(group) != bypassCodeGroupLen {
+				t.Fatalf("group %q in code %q has length %d, want %d", group, code, len(group), bypassCodeGroupLen)
+			}
+			for _, r := range group {
+				if !strings.ContainsRune(bypassCodeAlphabet, r) {
+					t.Fatalf("code %q contains %q, not in bypassCodeAlphabet %q", code, r, bypassCodeAlphabet)
+				}
+			}
+		}
+	}
+}
+
+func TestGenerateBypassCodeExcludesConfusableCharacters(t *testing.T) {
+	for _, confusable := range []rune{'0', 'O', '1', 'I'} {
+		if strings.ContainsRune(bypassCodeAlphabet, confusable) {
+			t.Fatalf("bypassCodeAlphabet %q should not contain %q", bypassCodeAlphabet, confusable)
+		}
+	}
+
+	for i := 0; i < 100; i++ {
+		code, err := GenerateBypassCode()
+		if err != nil {
+			t.Fatalf("GenerateBypassCode returned error: %v", err)
+		}
+		for _, confusable := range []rune{'0', 'O', '1', 'I'} {
+			if strings.ContainsRune(code, confusable) {
+				t.Fatalf("code %q contains confusable character %q", code, confusable)
+			}
+		}
+	}
+}
@@ -0,0 +1,34 @@
+package activationlock
+
+import (
+	"crypto/sha1" //nolint:gosec
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+
+	"golang.org/x/crypto/pbkdf2"
+)
+
+// Escrow key derivation parameters, per Apple's documentation for MDM
+// vendors implementing Activation Lock device locking.
+const (
+	escrowKeyIterations = 50000
+	escrowKeyLength     = 32 // bytes
+)
+
+// EscrowKey derives the Activation Lock escrow key for a bypass code
+// (generated by GenerateBypassCode) and a device's serial number. The
+// result is the hex-encoded PBKDF2-SHA1 of the bypass code, salted with the
+// SHA-256 digest of the serial number, and is the value sent to Apple as
+// the "escrow_key" of an ActivationLockRequest.
+//
+// The hyphens GenerateBypassCode inserts between groups are a transcription
+// convenience only and carry no cryptographic value, so they are stripped
+// before being used as the PBKDF2 password: the key must be derivable from
+// whatever canonical (no-dash) code a human re-enters at unlock time.
+func EscrowKey(bypassCode, serial string) string {
+	canonical := strings.ReplaceAll(bypassCode, "-", "")
+	salt := sha256.Sum256([]byte(serial))
+	key := pbkdf2.Key([]byte(canonical), salt[:], escrowKeyIterations, escrowKeyLength, sha1.New)
+	return hex.EncodeToString(key)
+}
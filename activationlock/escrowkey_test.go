@@ -0,0 +1,36 @@
+package activationlock
+
+import "testing"
+
+// TestEscrowKeyKnownAnswer pins EscrowKey's output against a fixed
+// bypass code and serial so that a future change cannot silently alter the
+// derivation (for example by reintroducing the hyphens into the PBKDF2
+// password) without the test catching it.
+func TestEscrowKeyKnownAnswer(t *testing.T) {
+	const (
+		bypassCode = "ABCDE-FGHJK-LMNPQ-RSTUV-WXYZ2-34567"
+		serial     = "C02ABC123XYZ"
+		want       = "db5cf21ed2f5a43f216ea2b08a8b0573b610068dd0023f200a9c3f627e306f7c"
+	)
+
+	got := EscrowKey(bypassCode, serial)
+	if got != want {
+		t.Fatalf("EscrowKey(%q, %q) = %q, want %q", bypassCode, serial, got, want)
+	}
+}
+
+// TestEscrowKeyIgnoresSeparators verifies that the hyphens GenerateBypassCode
+// inserts between groups do not affect the derived escrow key: Apple (or a
+// human) recomputing it from the canonical, no-dash code must arrive at the
+// same value.
+func TestEscrowKeyIgnoresSeparators(t *testing.T) {
+	const serial = "C02ABC123XYZ"
+	withDashes := "ABCDE-FGHJK-LMNPQ-RSTUV-WXYZ2-34567"
+	withoutDashes := "ABCDEFGHJKLMNPQRSTUVWXYZ234567"
+
+	got := EscrowKey(withDashes, serial)
+	want := EscrowKey(withoutDashes, serial)
+	if got != want {
+		t.Fatalf("EscrowKey differs with/without hyphens: %q != %q", got, want)
+	}
+}
@@ -0,0 +1,64 @@
+package godep
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/micromdm/nanodep/activationlock"
+)
+
+// ActivationLockRequest corresponds to the Apple DEP API
+// "ActivationLockRequest" structure.
+// See https://developer.apple.com/documentation/devicemanagement/activationlockrequest
+type ActivationLockRequest struct {
+	Device      string `json:"device"`
+	EscrowKey   string `json:"escrow_key"`
+	LostMessage string `json:"lost_message,omitempty"`
+}
+
+// ActivationLockResponse corresponds to the Apple DEP API
+// "ActivationLockResponse" structure.
+// See https://developer.apple.com/documentation/devicemanagement/activationlockresponse
+type ActivationLockResponse struct {
+	Device      string `json:"device"`
+	EscrowKey   string `json:"escrow_key"`
+	LostMessage string `json:"lost_message"`
+}
+
+// ActivationLock uses the Apple "Device Lock" DEP API endpoint to enable
+// Activation Lock on a single device without erasing it.
+// The name parameter specifies the configured DEP name to use.
+// See https://developer.apple.com/documentation/devicemanagement/device_lock
+func (c *Client) ActivationLock(ctx context.Context, name string, req *ActivationLockRequest) (*ActivationLockResponse, error) {
+	resp := new(ActivationLockResponse)
+	return resp, c.do(ctx, name, http.MethodPost, "/device/activationlock", req, resp)
+}
+
+// LockDeviceResult is the result of LockDevice: the bypass code generated
+// for the device alongside Apple's response to the ActivationLock request.
+// The BypassCode must be stored by the caller; it is the only way to later
+// remove Activation Lock from the device and Apple does not return it.
+type LockDeviceResult struct {
+	BypassCode string
+	Response   *ActivationLockResponse
+}
+
+// LockDevice generates an Activation Lock bypass code for serial and uses
+// it to enable Activation Lock via ActivationLock, returning the generated
+// bypass code alongside Apple's response so that the caller can store it
+// for later use.
+func (c *Client) LockDevice(ctx context.Context, name, serial, lostMessage string) (*LockDeviceResult, error) {
+	bypassCode, err := activationlock.GenerateBypassCode()
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.ActivationLock(ctx, name, &ActivationLockRequest{
+		Device:      serial,
+		EscrowKey:   activationlock.EscrowKey(bypassCode, serial),
+		LostMessage: lostMessage,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &LockDeviceResult{BypassCode: bypassCode, Response: resp}, nil
+}
@@ -3,6 +3,7 @@ package godep
 import (
 	"context"
 	"net/http"
+	"net/url"
 )
 
 // Profile corresponds to the Apple DEP API "Profile" structure.
@@ -81,6 +82,24 @@ func (c *Client) DefineProfile(ctx context.Context, name string, profile *Profil
 	return resp, c.do(ctx, name, http.MethodPost, "/profile", profile, resp)
 }
 
+// FetchProfile uses the Apple "Fetch the Details of a Profile" API endpoint
+// to retrieve a previously defined profile by its UUID.
+// The name parameter specifies the configured DEP name to use.
+// Unlike the device-scoped DEP endpoints (e.g. "/profile/devices"), the
+// profile_uuid here is a query parameter on the "/profile" collection
+// rather than a path segment, per Apple's documented request shape.
+// See https://developer.apple.com/documentation/devicemanagement/fetch_the_details_of_a_profile
+func (c *Client) FetchProfile(ctx context.Context, name, profileUUID string) (*Profile, error) {
+	resp := new(Profile)
+	return resp, c.do(ctx, name, http.MethodGet, fetchProfilePath(profileUUID), nil, resp)
+}
+
+// fetchProfilePath builds the request path for FetchProfile, split out so
+// the URL construction can be tested without a Client.
+func fetchProfilePath(profileUUID string) string {
+	return "/profile?" + url.Values{"profile_uuid": {profileUUID}}.Encode()
+}
+
 // ClearProfileResponse corresponds to the Apple DEP API "ClearProfileResponse" structure.
 // See https://developer.apple.com/documentation/devicemanagement/clearprofileresponse
 type ClearProfileResponse struct {
@@ -0,0 +1,202 @@
+package godep
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+const (
+	// defaultBatchSize is the default number of serials sent to Apple in a
+	// single AssignProfile or RemoveProfile call, keeping requests under
+	// Apple's per-request device cap for these endpoints.
+	defaultBatchSize = 1000
+
+	// defaultBatchConcurrency is the default number of batches sent to
+	// Apple concurrently.
+	defaultBatchConcurrency = 4
+)
+
+// BatchOption configures the chunking and concurrency behavior of
+// AssignProfileBatch and RemoveProfileBatch.
+type BatchOption func(*batchConfig)
+
+type batchConfig struct {
+	size        int
+	concurrency int
+}
+
+// WithBatchSize sets the number of serials sent to Apple per request.
+func WithBatchSize(size int) BatchOption {
+	return func(c *batchConfig) { c.size = size }
+}
+
+// WithBatchConcurrency sets the number of batches sent to Apple at once.
+func WithBatchConcurrency(concurrency int) BatchOption {
+	return func(c *batchConfig) { c.concurrency = concurrency }
+}
+
+func newBatchConfig(opts ...BatchOption) *batchConfig {
+	c := &batchConfig{size: defaultBatchSize, concurrency: defaultBatchConcurrency}
+	for _, opt := range opts {
+		opt(c)
+	}
+	// A non-positive concurrency would make sem below an unbuffered (or,
+	// for a negative value, invalid) channel, hanging or panicking before a
+	// single batch is ever sent. Fall back to the default the same way
+	// chunk() falls back on a non-positive size.
+	if c.concurrency <= 0 {
+		c.concurrency = defaultBatchConcurrency
+	}
+	return c
+}
+
+// AssignErrors classifies the serials returned by a batched AssignProfile or
+// RemoveProfile call by their per-device status string, so that callers do
+// not need to walk the merged Devices map themselves. Success holds every
+// serial Apple reported as "SUCCESS"; Failed holds every other status,
+// keyed by the status string Apple returned for it.
+type AssignErrors struct {
+	Success []string
+	Failed  map[string][]string
+}
+
+// Error implements the error interface. AssignErrors is returned (alongside
+// a non-nil ProfileResponse) whenever Apple reported a status other than
+// "SUCCESS" for at least one serial, so that callers who only check
+// err != nil still see a summary of what went wrong. Serials of a chunk
+// whose request failed outright have no status and are not counted here.
+func (e *AssignErrors) Error() string {
+	n := 0
+	for _, serials := range e.Failed {
+		n += len(serials)
+	}
+	return fmt.Sprintf("%d of %d devices did not succeed", n, n+len(e.Success))
+}
+
+func newAssignErrors(devices map[string]string) *AssignErrors {
+	e := &AssignErrors{Failed: make(map[string][]string)}
+	for serial, status := range devices {
+		if status == "SUCCESS" {
+			e.Success = append(e.Success, serial)
+			continue
+		}
+		e.Failed[status] = append(e.Failed[status], serial)
+	}
+	return e
+}
+
+// chunk splits serials into slices of at most size elements each.
+func chunk(serials []string, size int) [][]string {
+	if size <= 0 {
+		size = defaultBatchSize
+	}
+	var chunks [][]string
+	for size < len(serials) {
+		serials, chunks = serials[size:], append(chunks, serials[0:size:size])
+	}
+	return append(chunks, serials)
+}
+
+// AssignProfileBatch is AssignProfile for fleets larger than Apple's
+// per-request device cap. serials is split into chunks (1000 by default,
+// see WithBatchSize) which are assigned concurrently (see
+// WithBatchConcurrency), and the resulting ProfileResponse.Devices maps are
+// merged into a single response.
+//
+// If any serial's status is not "SUCCESS", the merged response is returned
+// alongside a non-nil *AssignErrors classifying every serial by status, so
+// that callers can distinguish "some devices failed" from a transport or
+// authentication error.
+func (c *Client) AssignProfileBatch(ctx context.Context, name, uuid string, serials []string, opts ...BatchOption) (*ProfileResponse, error) {
+	cfg := newBatchConfig(opts...)
+	return batchProfileOp(serials, cfg, func(batch []string) (map[string]string, error) {
+		resp, err := c.AssignProfile(ctx, name, uuid, batch...)
+		if resp == nil {
+			return nil, err
+		}
+		return resp.Devices, err
+	}, func(devices map[string]string) *ProfileResponse {
+		return &ProfileResponse{ProfileUUID: uuid, Devices: devices}
+	})
+}
+
+// RemoveProfileBatch is RemoveProfile for fleets larger than Apple's
+// per-request device cap. It chunks and merges results the same way as
+// AssignProfileBatch.
+func (c *Client) RemoveProfileBatch(ctx context.Context, name string, serials []string, opts ...BatchOption) (*ClearProfileResponse, error) {
+	cfg := newBatchConfig(opts...)
+	return batchProfileOp(serials, cfg, func(batch []string) (map[string]string, error) {
+		resp, err := c.RemoveProfile(ctx, name, batch)
+		if resp == nil {
+			return nil, err
+		}
+		return resp.Devices, err
+	}, func(devices map[string]string) *ClearProfileResponse {
+		return &ClearProfileResponse{Devices: devices}
+	})
+}
+
+// batchProfileOp runs op over chunks of serials with bounded concurrency,
+// merges the returned per-serial status maps, and wraps the merge with
+// build. It is shared by AssignProfileBatch and RemoveProfileBatch, which
+// differ only in which Client method they call and how they build their
+// response type.
+func batchProfileOp[T any](serials []string, cfg *batchConfig, op func(batch []string) (map[string]string, error), build func(devices map[string]string) *T) (*T, error) {
+	chunks := chunk(serials, cfg.size)
+
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		merged   = make(map[string]string, len(serials))
+		sem      = make(chan struct{}, cfg.concurrency)
+		firstErr error
+	)
+
+	for _, batch := range chunks {
+		batch := batch
+		wg.Add(1)
+		sem <- struct{}{}
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			devices, err := op(batch)
+
+			mu.Lock()
+			defer mu.Unlock()
+			for serial, status := range devices {
+				merged[serial] = status
+			}
+			if err != nil && firstErr == nil {
+				firstErr = err
+			}
+		}()
+	}
+	wg.Wait()
+
+	// Every chunk runs regardless of another chunk's outcome, so merged
+	// already holds every device any chunk successfully reported even if
+	// another chunk failed outright: surface that partial result alongside
+	// the error instead of discarding tens of thousands of other devices'
+	// results because one chunk hit a transient transport error.
+	resp := build(merged)
+
+	var errs []error
+	if firstErr != nil {
+		errs = append(errs, fmt.Errorf("%d of %d devices not submitted: %w", len(serials)-len(merged), len(serials), firstErr))
+	}
+	if assignErrs := newAssignErrors(merged); len(assignErrs.Failed) > 0 {
+		errs = append(errs, assignErrs)
+	}
+
+	switch len(errs) {
+	case 0:
+		return resp, nil
+	case 1:
+		return resp, errs[0]
+	default:
+		return resp, errors.Join(errs...)
+	}
+}
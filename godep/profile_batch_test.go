@@ -0,0 +1,77 @@
+package godep
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestChunk(t *testing.T) {
+	serials := []string{"a", "b", "c", "d", "e"}
+
+	got := chunk(serials, 2)
+	want := [][]string{{"a", "b"}, {"c", "d"}, {"e"}}
+	if len(got) != len(want) {
+		t.Fatalf("chunk() = %v, want %v", got, want)
+	}
+	for i := range want {
+		if len(got[i]) != len(want[i]) {
+			t.Fatalf("chunk()[%d] = %v, want %v", i, got[i], want[i])
+		}
+		for j := range want[i] {
+			if got[i][j] != want[i][j] {
+				t.Fatalf("chunk()[%d][%d] = %q, want %q", i, j, got[i][j], want[i][j])
+			}
+		}
+	}
+
+	// size <= 0 falls back to defaultBatchSize rather than looping forever
+	// or returning an empty chunk.
+	got = chunk(serials, 0)
+	if len(got) != 1 || len(got[0]) != len(serials) {
+		t.Fatalf("chunk(serials, 0) = %v, want a single chunk of %d", got, len(serials))
+	}
+}
+
+func TestNewBatchConfigClampsConcurrency(t *testing.T) {
+	for _, concurrency := range []int{0, -1, -100} {
+		cfg := newBatchConfig(WithBatchConcurrency(concurrency))
+		if cfg.concurrency != defaultBatchConcurrency {
+			t.Errorf("WithBatchConcurrency(%d): concurrency = %d, want %d", concurrency, cfg.concurrency, defaultBatchConcurrency)
+		}
+	}
+
+	cfg := newBatchConfig(WithBatchConcurrency(2))
+	if cfg.concurrency != 2 {
+		t.Errorf("WithBatchConcurrency(2): concurrency = %d, want 2", cfg.concurrency)
+	}
+}
+
+func TestBatchProfileOpPreservesResultsOnPartialError(t *testing.T) {
+	serials := []string{"s1", "s2", "s3", "s4"}
+	cfg := newBatchConfig(WithBatchSize(1), WithBatchConcurrency(1))
+
+	errBoom := errors.New("boom")
+	resp, err := batchProfileOp(serials, cfg, func(batch []string) (map[string]string, error) {
+		if batch[0] == "s2" {
+			return nil, errBoom
+		}
+		return map[string]string{batch[0]: "SUCCESS"}, nil
+	}, func(devices map[string]string) *ProfileResponse {
+		return &ProfileResponse{Devices: devices}
+	})
+
+	if err == nil {
+		t.Fatal("expected a non-nil error")
+	}
+	if resp == nil {
+		t.Fatal("expected a non-nil partial response alongside the error")
+	}
+	for _, serial := range []string{"s1", "s3", "s4"} {
+		if resp.Devices[serial] != "SUCCESS" {
+			t.Errorf("Devices[%q] = %q, want SUCCESS", serial, resp.Devices[serial])
+		}
+	}
+	if _, ok := resp.Devices["s2"]; ok {
+		t.Errorf("Devices[%q] should be absent, the chunk that owns it failed outright", "s2")
+	}
+}
@@ -0,0 +1,115 @@
+package godep
+
+import (
+	"context"
+	"fmt"
+)
+
+// profileDefiner is the subset of *Client that ProfileEnsurer depends on.
+// Depending on this instead of *Client directly lets tests exercise
+// EnsureProfile's branching against a fake without performing real DEP
+// requests.
+type profileDefiner interface {
+	DefineProfile(ctx context.Context, name string, profile *Profile) (*DefineProfileResponse, error)
+	FetchProfile(ctx context.Context, name, profileUUID string) (*Profile, error)
+}
+
+// ProfileEnsurer wraps a Client with a ProfileStore to provide the
+// idempotent EnsureProfile operation. It is held separately from Client
+// itself since, unlike the other profile operations, it requires a place to
+// persist state between calls; NewProfileEnsurer(client, store).EnsureProfile(...)
+// is used in place of a client.EnsureProfile(...) method directly on Client.
+type ProfileEnsurer struct {
+	client     profileDefiner
+	store      ProfileStore
+	verify     bool
+	isNotFound func(error) bool
+}
+
+// EnsureProfileOption configures a ProfileEnsurer.
+type EnsureProfileOption func(*ProfileEnsurer)
+
+// WithVerify causes EnsureProfile to confirm a cached profile UUID against
+// Apple (via FetchProfile) before trusting it, rather than trusting the
+// store alone. This catches the case where Apple's records and the local
+// store have drifted apart, at the cost of an extra API call per check.
+//
+// By default, any error from that FetchProfile call is treated as a
+// transient failure and returned to the caller: DefineProfile always mints
+// a brand-new profile at Apple, so falling back to it on an error we can't
+// positively identify as "Apple has forgotten this profile" would silently
+// create a duplicate, orphaned profile on every blip. Pass
+// WithNotFoundChecker to let EnsureProfile tell the two cases apart.
+func WithVerify() EnsureProfileOption {
+	return func(e *ProfileEnsurer) { e.verify = true }
+}
+
+// WithNotFoundChecker configures the function WithVerify uses to recognize
+// that Apple has no record of a profile UUID (as opposed to some other,
+// transient, fetch failure). When isNotFound(err) reports true for the
+// error returned by FetchProfile, EnsureProfile treats the cached UUID as
+// stale and defines the profile fresh instead of returning the error.
+func WithNotFoundChecker(isNotFound func(error) bool) EnsureProfileOption {
+	return func(e *ProfileEnsurer) { e.isNotFound = isNotFound }
+}
+
+// NewProfileEnsurer creates a ProfileEnsurer that calls through to client
+// and persists profile state in store.
+func NewProfileEnsurer(client profileDefiner, store ProfileStore, opts ...EnsureProfileOption) *ProfileEnsurer {
+	e := &ProfileEnsurer{client: client, store: store}
+	for _, opt := range opts {
+		opt(e)
+	}
+	return e
+}
+
+// EnsureProfile declaratively defines profile under the given DEP name. It
+// only calls Apple's "Define a Profile" API when necessary: if a prior call
+// already defined an identical profile (same fingerprint, per
+// profileFingerprint) then the cached DefineProfileResponse is synthesized
+// from the store and changed is false. Otherwise profile is defined with
+// Apple, the store is updated, and changed is true.
+//
+// If the ProfileEnsurer was created with WithVerify, a cache hit is
+// additionally confirmed against Apple with FetchProfile before being
+// trusted. If that fetch fails, the error is classified with the
+// WithNotFoundChecker function (if any): only an error identified as
+// "Apple no longer knows about this UUID" causes EnsureProfile to fall back
+// to defining the profile fresh, otherwise the fetch error is returned as
+// EnsureProfile's error, the same as any other transient failure.
+func (e *ProfileEnsurer) EnsureProfile(ctx context.Context, name string, profile *Profile) (*DefineProfileResponse, bool, error) {
+	fingerprint := profileFingerprint(profile)
+
+	record, err := e.store.GetProfile(ctx, name, profile.ProfileName)
+	if err != nil {
+		return nil, false, err
+	}
+
+	if record != nil && record.Fingerprint == fingerprint {
+		if e.verify {
+			if _, err := e.client.FetchProfile(ctx, name, record.ProfileUUID); err != nil {
+				if e.isNotFound == nil || !e.isNotFound(err) {
+					return nil, false, fmt.Errorf("verifying cached profile %s: %w", record.ProfileUUID, err)
+				}
+				record = nil
+			}
+		}
+		if record != nil {
+			return &DefineProfileResponse{ProfileUUID: record.ProfileUUID}, false, nil
+		}
+	}
+
+	resp, err := e.client.DefineProfile(ctx, name, profile)
+	if err != nil {
+		return nil, false, err
+	}
+
+	if err := e.store.PutProfile(ctx, name, profile.ProfileName, &ProfileRecord{
+		Fingerprint: fingerprint,
+		ProfileUUID: resp.ProfileUUID,
+	}); err != nil {
+		return nil, false, err
+	}
+
+	return resp, true, nil
+}
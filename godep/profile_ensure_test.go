@@ -0,0 +1,152 @@
+package godep
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+// fakeProfileDefiner is a profileDefiner stand-in that lets tests control
+// FetchProfile's outcome and count DefineProfile calls without performing
+// real DEP requests.
+type fakeProfileDefiner struct {
+	fetchErr    error
+	defineCalls int
+	defineUUID  string
+}
+
+func (f *fakeProfileDefiner) DefineProfile(_ context.Context, _ string, _ *Profile) (*DefineProfileResponse, error) {
+	f.defineCalls++
+	return &DefineProfileResponse{ProfileUUID: f.defineUUID}, nil
+}
+
+func (f *fakeProfileDefiner) FetchProfile(_ context.Context, _, _ string) (*Profile, error) {
+	if f.fetchErr != nil {
+		return nil, f.fetchErr
+	}
+	return &Profile{}, nil
+}
+
+const testDEPName = "testdep"
+
+func testProfile() *Profile {
+	return &Profile{ProfileName: "Test", OrgMagic: "magic"}
+}
+
+func TestEnsureProfileNoCachedRecord(t *testing.T) {
+	client := &fakeProfileDefiner{defineUUID: "uuid-1"}
+	ensurer := NewProfileEnsurer(client, NewMemoryProfileStore())
+
+	resp, changed, err := ensurer.EnsureProfile(context.Background(), testDEPName, testProfile())
+	if err != nil {
+		t.Fatalf("EnsureProfile returned error: %v", err)
+	}
+	if !changed {
+		t.Error("changed = false, want true on first call")
+	}
+	if resp.ProfileUUID != "uuid-1" {
+		t.Errorf("ProfileUUID = %q, want %q", resp.ProfileUUID, "uuid-1")
+	}
+	if client.defineCalls != 1 {
+		t.Errorf("DefineProfile called %d times, want 1", client.defineCalls)
+	}
+}
+
+func TestEnsureProfileFingerprintMismatchRedefines(t *testing.T) {
+	client := &fakeProfileDefiner{defineUUID: "uuid-2"}
+	store := NewMemoryProfileStore()
+	store.PutProfile(context.Background(), testDEPName, "Test", &ProfileRecord{
+		Fingerprint: "stale-fingerprint",
+		ProfileUUID: "uuid-old",
+	})
+	ensurer := NewProfileEnsurer(client, store)
+
+	resp, changed, err := ensurer.EnsureProfile(context.Background(), testDEPName, testProfile())
+	if err != nil {
+		t.Fatalf("EnsureProfile returned error: %v", err)
+	}
+	if !changed {
+		t.Error("changed = false, want true on fingerprint mismatch")
+	}
+	if resp.ProfileUUID != "uuid-2" {
+		t.Errorf("ProfileUUID = %q, want %q", resp.ProfileUUID, "uuid-2")
+	}
+	if client.defineCalls != 1 {
+		t.Errorf("DefineProfile called %d times, want 1", client.defineCalls)
+	}
+}
+
+func TestEnsureProfileCacheHitWithoutVerify(t *testing.T) {
+	client := &fakeProfileDefiner{defineUUID: "uuid-should-not-be-used"}
+	profile := testProfile()
+	store := NewMemoryProfileStore()
+	store.PutProfile(context.Background(), testDEPName, profile.ProfileName, &ProfileRecord{
+		Fingerprint: profileFingerprint(profile),
+		ProfileUUID: "uuid-cached",
+	})
+	ensurer := NewProfileEnsurer(client, store)
+
+	resp, changed, err := ensurer.EnsureProfile(context.Background(), testDEPName, profile)
+	if err != nil {
+		t.Fatalf("EnsureProfile returned error: %v", err)
+	}
+	if changed {
+		t.Error("changed = true, want false on an unchanged cache hit")
+	}
+	if resp.ProfileUUID != "uuid-cached" {
+		t.Errorf("ProfileUUID = %q, want %q", resp.ProfileUUID, "uuid-cached")
+	}
+	if client.defineCalls != 0 {
+		t.Errorf("DefineProfile called %d times, want 0", client.defineCalls)
+	}
+}
+
+func TestEnsureProfileVerifyUnclassifiedErrorIsReturnedNotSwallowed(t *testing.T) {
+	fetchErr := errors.New("connection reset")
+	client := &fakeProfileDefiner{fetchErr: fetchErr, defineUUID: "uuid-should-not-be-used"}
+	profile := testProfile()
+	store := NewMemoryProfileStore()
+	store.PutProfile(context.Background(), testDEPName, profile.ProfileName, &ProfileRecord{
+		Fingerprint: profileFingerprint(profile),
+		ProfileUUID: "uuid-cached",
+	})
+	ensurer := NewProfileEnsurer(client, store, WithVerify())
+
+	_, _, err := ensurer.EnsureProfile(context.Background(), testDEPName, profile)
+	if err == nil {
+		t.Fatal("expected an error for an unclassified verify failure, got nil")
+	}
+	if !errors.Is(err, fetchErr) {
+		t.Errorf("error %v does not wrap the underlying fetch error %v", err, fetchErr)
+	}
+	if client.defineCalls != 0 {
+		t.Errorf("DefineProfile called %d times, want 0 (an unclassified error must not trigger a redefine)", client.defineCalls)
+	}
+}
+
+func TestEnsureProfileVerifyNotFoundFallsBackToDefine(t *testing.T) {
+	fetchErr := errors.New("404 not found")
+	client := &fakeProfileDefiner{fetchErr: fetchErr, defineUUID: "uuid-fresh"}
+	profile := testProfile()
+	store := NewMemoryProfileStore()
+	store.PutProfile(context.Background(), testDEPName, profile.ProfileName, &ProfileRecord{
+		Fingerprint: profileFingerprint(profile),
+		ProfileUUID: "uuid-forgotten",
+	})
+	isNotFound := func(err error) bool { return errors.Is(err, fetchErr) }
+	ensurer := NewProfileEnsurer(client, store, WithVerify(), WithNotFoundChecker(isNotFound))
+
+	resp, changed, err := ensurer.EnsureProfile(context.Background(), testDEPName, profile)
+	if err != nil {
+		t.Fatalf("EnsureProfile returned error: %v", err)
+	}
+	if !changed {
+		t.Error("changed = false, want true when Apple has forgotten the cached UUID")
+	}
+	if resp.ProfileUUID != "uuid-fresh" {
+		t.Errorf("ProfileUUID = %q, want %q", resp.ProfileUUID, "uuid-fresh")
+	}
+	if client.defineCalls != 1 {
+		t.Errorf("DefineProfile called %d times, want 1", client.defineCalls)
+	}
+}
@@ -0,0 +1,26 @@
+package godep
+
+import (
+	"net/url"
+	"testing"
+)
+
+// TestFetchProfilePath pins FetchProfile's request contract: a GET against
+// the "/profile" collection with profile_uuid as a query parameter, per
+// Apple's documented "Fetch the Details of a Profile" endpoint (as opposed
+// to a "/profile/{uuid}" path-parameter route, as some other DEP client
+// libraries use for analogous single-resource fetches).
+func TestFetchProfilePath(t *testing.T) {
+	got := fetchProfilePath("11111111-2222-3333-4444-555555555555")
+
+	u, err := url.Parse(got)
+	if err != nil {
+		t.Fatalf("fetchProfilePath returned an unparsable path %q: %v", got, err)
+	}
+	if u.Path != "/profile" {
+		t.Errorf("path = %q, want %q", u.Path, "/profile")
+	}
+	if got := u.Query().Get("profile_uuid"); got != "11111111-2222-3333-4444-555555555555" {
+		t.Errorf("profile_uuid query param = %q, want %q", got, "11111111-2222-3333-4444-555555555555")
+	}
+}
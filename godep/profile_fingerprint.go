@@ -0,0 +1,53 @@
+package godep
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"sort"
+)
+
+// profileFingerprint computes a stable, content-based fingerprint of a
+// Profile. Two profiles that are equivalent except for field ordering
+// (SkipSetupItems, AnchorCerts, SupervisingHostCerts), the server-assigned
+// ProfileUUID, or their assigned Devices produce the same fingerprint.
+//
+// Devices is deliberately excluded: it is mutated by AssignProfile and
+// RemoveProfile, not by redefining the profile, and DefineProfile has no
+// update-in-place, so folding it into the fingerprint would make ordinary
+// device assignment churn mint a brand-new Apple profile UUID on every
+// EnsureProfile call.
+//
+// profileFingerprint is used by EnsureProfile to decide whether a profile
+// needs to be re-defined with Apple.
+func profileFingerprint(profile *Profile) string {
+	canon := *profile
+	canon.ProfileUUID = ""
+	canon.Devices = nil
+
+	canon.SkipSetupItems = sortedCopy(canon.SkipSetupItems)
+	canon.AnchorCerts = sortedCopy(canon.AnchorCerts)
+	canon.SupervisingHostCerts = sortedCopy(canon.SupervisingHostCerts)
+
+	// json.Marshal on a struct always emits fields in the order they are
+	// declared, so this is already canonical with respect to key ordering.
+	b, err := json.Marshal(&canon)
+	if err != nil {
+		// Profile only contains strings, bools, and string slices, so
+		// marshaling can never fail.
+		panic(err)
+	}
+
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:])
+}
+
+func sortedCopy(s []string) []string {
+	if s == nil {
+		return nil
+	}
+	out := make([]string, len(s))
+	copy(out, s)
+	sort.Strings(out)
+	return out
+}
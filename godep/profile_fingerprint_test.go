@@ -0,0 +1,53 @@
+package godep
+
+import "testing"
+
+func TestProfileFingerprintIgnoresDevicesAndUUID(t *testing.T) {
+	base := &Profile{
+		ProfileName: "Test",
+		URL:         "https://example.com/mdm",
+		OrgMagic:    "magic",
+	}
+
+	baseline := profileFingerprint(base)
+
+	withDevices := *base
+	withDevices.Devices = []string{"SERIAL1", "SERIAL2"}
+	if got := profileFingerprint(&withDevices); got != baseline {
+		t.Errorf("adding Devices changed the fingerprint: %q != %q", got, baseline)
+	}
+
+	withUUID := *base
+	withUUID.ProfileUUID = "11111111-1111-1111-1111-111111111111"
+	if got := profileFingerprint(&withUUID); got != baseline {
+		t.Errorf("setting ProfileUUID changed the fingerprint: %q != %q", got, baseline)
+	}
+}
+
+func TestProfileFingerprintOrderInvariant(t *testing.T) {
+	a := &Profile{
+		ProfileName:    "Test",
+		OrgMagic:       "magic",
+		SkipSetupItems: []string{"Location", "Biometric"},
+		AnchorCerts:    []string{"cert-b", "cert-a"},
+	}
+	b := &Profile{
+		ProfileName:    "Test",
+		OrgMagic:       "magic",
+		SkipSetupItems: []string{"Biometric", "Location"},
+		AnchorCerts:    []string{"cert-a", "cert-b"},
+	}
+
+	if profileFingerprint(a) != profileFingerprint(b) {
+		t.Error("fingerprint should be invariant to SkipSetupItems/AnchorCerts ordering")
+	}
+}
+
+func TestProfileFingerprintDetectsContentChange(t *testing.T) {
+	a := &Profile{ProfileName: "Test", OrgMagic: "magic", IsMandatory: false}
+	b := &Profile{ProfileName: "Test", OrgMagic: "magic", IsMandatory: true}
+
+	if profileFingerprint(a) == profileFingerprint(b) {
+		t.Error("fingerprint should change when profile content changes")
+	}
+}
@@ -0,0 +1,61 @@
+package godep
+
+import (
+	"context"
+	"sync"
+)
+
+// ProfileRecord is the state EnsureProfile persists for a single
+// (DEP name, profile name) pair: the fingerprint of the Profile that was
+// last defined with Apple and the UUID Apple assigned to it.
+type ProfileRecord struct {
+	Fingerprint string
+	ProfileUUID string
+}
+
+// ProfileStore persists the ProfileRecord for a profile so that EnsureProfile
+// can tell, across calls, whether a profile has already been defined with
+// Apple and whether its contents have since changed.
+//
+// GetProfile returns (nil, nil) if no record exists for depName/profileName.
+type ProfileStore interface {
+	GetProfile(ctx context.Context, depName, profileName string) (*ProfileRecord, error)
+	PutProfile(ctx context.Context, depName, profileName string, record *ProfileRecord) error
+}
+
+type profileStoreKey struct {
+	depName     string
+	profileName string
+}
+
+// MemoryProfileStore is a ProfileStore backed by an in-memory map. It is
+// primarily useful for tests and single-process tools; records do not
+// survive a process restart.
+type MemoryProfileStore struct {
+	mu      sync.RWMutex
+	records map[profileStoreKey]ProfileRecord
+}
+
+// NewMemoryProfileStore creates a new, empty MemoryProfileStore.
+func NewMemoryProfileStore() *MemoryProfileStore {
+	return &MemoryProfileStore{records: make(map[profileStoreKey]ProfileRecord)}
+}
+
+// GetProfile implements ProfileStore.
+func (s *MemoryProfileStore) GetProfile(_ context.Context, depName, profileName string) (*ProfileRecord, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	record, ok := s.records[profileStoreKey{depName, profileName}]
+	if !ok {
+		return nil, nil
+	}
+	return &record, nil
+}
+
+// PutProfile implements ProfileStore.
+func (s *MemoryProfileStore) PutProfile(_ context.Context, depName, profileName string, record *ProfileRecord) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.records[profileStoreKey{depName, profileName}] = *record
+	return nil
+}
@@ -0,0 +1,74 @@
+package godep
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"sync"
+)
+
+// FileProfileStore is a ProfileStore that persists its records as a single
+// JSON file on disk. It is suitable for single-process command-line tools
+// that want EnsureProfile's idempotency to survive across runs without
+// standing up a database.
+type FileProfileStore struct {
+	path string
+	mu   sync.Mutex
+}
+
+// NewFileProfileStore creates a FileProfileStore backed by the file at path.
+// The file is created on first write; it is not required to exist yet.
+func NewFileProfileStore(path string) *FileProfileStore {
+	return &FileProfileStore{path: path}
+}
+
+func (s *FileProfileStore) load() (map[string]map[string]ProfileRecord, error) {
+	records := make(map[string]map[string]ProfileRecord)
+	b, err := os.ReadFile(s.path)
+	if os.IsNotExist(err) {
+		return records, nil
+	} else if err != nil {
+		return nil, err
+	}
+	if len(b) == 0 {
+		return records, nil
+	}
+	if err := json.Unmarshal(b, &records); err != nil {
+		return nil, err
+	}
+	return records, nil
+}
+
+// GetProfile implements ProfileStore.
+func (s *FileProfileStore) GetProfile(_ context.Context, depName, profileName string) (*ProfileRecord, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	records, err := s.load()
+	if err != nil {
+		return nil, err
+	}
+	record, ok := records[depName][profileName]
+	if !ok {
+		return nil, nil
+	}
+	return &record, nil
+}
+
+// PutProfile implements ProfileStore.
+func (s *FileProfileStore) PutProfile(_ context.Context, depName, profileName string, record *ProfileRecord) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	records, err := s.load()
+	if err != nil {
+		return err
+	}
+	if records[depName] == nil {
+		records[depName] = make(map[string]ProfileRecord)
+	}
+	records[depName][profileName] = *record
+	b, err := json.MarshalIndent(records, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(s.path, b, 0600)
+}
@@ -0,0 +1,90 @@
+package godep
+
+import (
+	"context"
+	"testing"
+)
+
+func TestMemoryProfileStoreRoundTrip(t *testing.T) {
+	store := NewMemoryProfileStore()
+	ctx := context.Background()
+
+	got, err := store.GetProfile(ctx, "dep1", "profile1")
+	if err != nil {
+		t.Fatalf("GetProfile on empty store returned error: %v", err)
+	}
+	if got != nil {
+		t.Fatalf("GetProfile on empty store = %+v, want nil", got)
+	}
+
+	want := &ProfileRecord{Fingerprint: "fp1", ProfileUUID: "uuid1"}
+	if err := store.PutProfile(ctx, "dep1", "profile1", want); err != nil {
+		t.Fatalf("PutProfile returned error: %v", err)
+	}
+
+	got, err = store.GetProfile(ctx, "dep1", "profile1")
+	if err != nil {
+		t.Fatalf("GetProfile returned error: %v", err)
+	}
+	if got == nil || *got != *want {
+		t.Fatalf("GetProfile = %+v, want %+v", got, want)
+	}
+
+	if got, _ := store.GetProfile(ctx, "dep1", "other-profile"); got != nil {
+		t.Fatalf("GetProfile for a different profile name = %+v, want nil", got)
+	}
+}
+
+func TestFileProfileStoreRoundTrip(t *testing.T) {
+	path := t.TempDir() + "/profiles.json"
+	store := NewFileProfileStore(path)
+	ctx := context.Background()
+
+	got, err := store.GetProfile(ctx, "dep1", "profile1")
+	if err != nil {
+		t.Fatalf("GetProfile before any write returned error: %v", err)
+	}
+	if got != nil {
+		t.Fatalf("GetProfile before any write = %+v, want nil", got)
+	}
+
+	records := map[string]map[string]*ProfileRecord{
+		"dep1": {
+			"profile1": {Fingerprint: "fp1", ProfileUUID: "uuid1"},
+			"profile2": {Fingerprint: "fp2", ProfileUUID: "uuid2"},
+		},
+		"dep2": {
+			"profile1": {Fingerprint: "fp3", ProfileUUID: "uuid3"},
+		},
+	}
+	for depName, profiles := range records {
+		for profileName, record := range profiles {
+			if err := store.PutProfile(ctx, depName, profileName, record); err != nil {
+				t.Fatalf("PutProfile(%s, %s) returned error: %v", depName, profileName, err)
+			}
+		}
+	}
+
+	for depName, profiles := range records {
+		for profileName, want := range profiles {
+			got, err := store.GetProfile(ctx, depName, profileName)
+			if err != nil {
+				t.Fatalf("GetProfile(%s, %s) returned error: %v", depName, profileName, err)
+			}
+			if got == nil || *got != *want {
+				t.Errorf("GetProfile(%s, %s) = %+v, want %+v", depName, profileName, got, want)
+			}
+		}
+	}
+
+	// A second FileProfileStore over the same path sees everything the
+	// first one wrote.
+	reopened := NewFileProfileStore(path)
+	got, err = reopened.GetProfile(ctx, "dep2", "profile1")
+	if err != nil {
+		t.Fatalf("GetProfile on reopened store returned error: %v", err)
+	}
+	if got == nil || got.ProfileUUID != "uuid3" {
+		t.Fatalf("GetProfile on reopened store = %+v, want ProfileUUID uuid3", got)
+	}
+}